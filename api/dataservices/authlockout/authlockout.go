@@ -0,0 +1,91 @@
+package authlockout
+
+import (
+	"encoding/json"
+
+	"github.com/portainer/portainer/api/dataservices"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// BucketName is the bbolt bucket backing this store, one row per lockout key.
+const BucketName = "auth_lockouts"
+
+// Service is the bbolt-backed dataservices.AuthLockoutService implementation.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a Service backed by db, creating BucketName if it doesn't already exist.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(BucketName))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{db: db}, nil
+}
+
+// GetByKey returns the record for key, or dataservices.ErrObjectNotFound if none exists.
+func (service *Service) GetByKey(key string) (*dataservices.AuthLockoutRecord, error) {
+	var record dataservices.AuthLockoutRecord
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket([]byte(BucketName)).Get([]byte(key))
+		if data == nil {
+			return dataservices.ErrObjectNotFound
+		}
+
+		return json.Unmarshal(data, &record)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Update persists record, keyed by record.Key, creating it if it doesn't already exist.
+func (service *Service) Update(record *dataservices.AuthLockoutRecord) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket([]byte(BucketName)).Put([]byte(record.Key), data)
+	})
+}
+
+// All returns every lockout record currently on file.
+func (service *Service) All() ([]*dataservices.AuthLockoutRecord, error) {
+	var records []*dataservices.AuthLockoutRecord
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(BucketName)).ForEach(func(k, v []byte) error {
+			var record dataservices.AuthLockoutRecord
+			if err := json.Unmarshal(v, &record); err != nil {
+				return err
+			}
+
+			records = append(records, &record)
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// Delete removes the record for key, if any.
+func (service *Service) Delete(key string) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(BucketName)).Delete([]byte(key))
+	})
+}
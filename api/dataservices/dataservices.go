@@ -0,0 +1,104 @@
+// Package dataservices declares Portainer's datastore interface: one sub-store per concern,
+// reachable off DataStore by name. This file only covers the slice api/jwt depends on; the real
+// DataStore interface this lives alongside has many more sub-stores for the rest of the API.
+package dataservices
+
+import (
+	"time"
+
+	portainer "github.com/portainer/portainer/api"
+)
+
+// UserService and SettingsService predate this series; declared here only so DataStore is
+// self-contained for the sub-stores added alongside it.
+type UserService interface {
+	Read(ID portainer.UserID) (*portainer.User, error)
+}
+
+type SettingsService interface {
+	Settings() (*portainer.Settings, error)
+	UpdateSettings(settings *portainer.Settings) error
+}
+
+// DataStore is the subset of Portainer's datastore that api/jwt depends on.
+type DataStore interface {
+	User() UserService
+	Settings() SettingsService
+	TokenRevocation() TokenRevocationService
+	Keyring() KeyringService
+	Session() SessionService
+	AuthLockout() AuthLockoutService
+}
+
+// TokenRevocationRecord tracks one issued token's revocation/idle state, keyed by its jti.
+type TokenRevocationRecord struct {
+	JTI        string
+	UserID     portainer.UserID
+	Revoked    bool
+	LastUsedAt time.Time
+}
+
+// TokenRevocationService manages per-token revocation and idle-timeout bookkeeping.
+type TokenRevocationService interface {
+	Create(record *TokenRevocationRecord) error
+	Update(record *TokenRevocationRecord) error
+	Delete(jti string) error
+	GetByJTI(jti string) (*TokenRevocationRecord, error)
+	Revoke(jti string) error
+	RevokeAllForUser(userID portainer.UserID) error
+}
+
+// KeyringEntry is one signing key generated for a scope, retained for verification until it ages
+// out of keyRetentionCount.
+type KeyringEntry struct {
+	KID        string
+	Scope      string
+	Algorithm  string
+	PrivateKey []byte
+	CreatedAt  time.Time
+}
+
+// KeyringService manages the signing keys minted for each JWT scope.
+type KeyringService interface {
+	AllForScope(scope string) ([]*KeyringEntry, error)
+	Create(entry *KeyringEntry) error
+}
+
+// SessionRecord represents one logged-in device/session. It persists across token refreshes (see
+// jwt.Service.issueTokenPair), so that it reflects one row per login rather than per refresh.
+type SessionRecord struct {
+	SessionID  string
+	UserID     portainer.UserID
+	DeviceID   string
+	IP         string
+	UserAgent  string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	Terminated bool
+}
+
+// SessionService manages per-device session records.
+type SessionService interface {
+	Create(record *SessionRecord) error
+	Update(record *SessionRecord) error
+	GetByID(sessionID string) (*SessionRecord, error)
+	ListForUser(userID portainer.UserID) ([]*SessionRecord, error)
+	Terminate(sessionID string) error
+}
+
+// AuthLockoutRecord tracks the failure count in the current window and current lockout for one key (a
+// "user:<username>" or "ip:<ip>" pair; see jwt.authLockoutKey).
+type AuthLockoutRecord struct {
+	Key          string
+	WindowStart  time.Time
+	FailureCount int
+	LockedUntil  time.Time
+}
+
+// AuthLockoutService manages brute-force lockout bookkeeping.
+type AuthLockoutService interface {
+	GetByKey(key string) (*AuthLockoutRecord, error)
+	Update(record *AuthLockoutRecord) error
+	All() ([]*AuthLockoutRecord, error)
+	Delete(key string) error
+}
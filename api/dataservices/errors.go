@@ -0,0 +1,14 @@
+package dataservices
+
+import "errors"
+
+// ErrObjectNotFound is returned by a sub-store's read methods when no record exists for the given
+// key.
+var ErrObjectNotFound = errors.New("Object not found")
+
+// IsErrObjectNotFound reports whether err is (or wraps) ErrObjectNotFound, so that callers can
+// tell a missing record apart from a genuine datastore failure without comparing against a
+// concrete not-found type.
+func IsErrObjectNotFound(err error) bool {
+	return errors.Is(err, ErrObjectNotFound)
+}
@@ -0,0 +1,68 @@
+package keyring
+
+import (
+	"encoding/json"
+
+	"github.com/portainer/portainer/api/dataservices"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// BucketName is the bbolt bucket backing this store, one row per KID.
+const BucketName = "jwt_signing_keys"
+
+// Service is the bbolt-backed dataservices.KeyringService implementation.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a Service backed by db, creating BucketName if it doesn't already exist.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(BucketName))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{db: db}, nil
+}
+
+// Create persists entry, keyed by its KID.
+func (service *Service) Create(entry *dataservices.KeyringEntry) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket([]byte(BucketName)).Put([]byte(entry.KID), data)
+	})
+}
+
+// AllForScope returns every entry minted for scope, in no particular order; callers needing the
+// most recent one sort by CreatedAt themselves (see jwt.newestEntry/retainLatest).
+func (service *Service) AllForScope(scope string) ([]*dataservices.KeyringEntry, error) {
+	var entries []*dataservices.KeyringEntry
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(BucketName)).ForEach(func(k, v []byte) error {
+			var entry dataservices.KeyringEntry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+
+			if entry.Scope == scope {
+				entries = append(entries, &entry)
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
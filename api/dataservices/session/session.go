@@ -0,0 +1,114 @@
+package session
+
+import (
+	"encoding/json"
+	"sort"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// BucketName is the bbolt bucket backing this store, one row per session id.
+const BucketName = "sessions"
+
+// Service is the bbolt-backed dataservices.SessionService implementation.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a Service backed by db, creating BucketName if it doesn't already exist.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(BucketName))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{db: db}, nil
+}
+
+// Create persists record, keyed by its SessionID.
+func (service *Service) Create(record *dataservices.SessionRecord) error {
+	return service.put(record)
+}
+
+// Update overwrites the stored record for record.SessionID.
+func (service *Service) Update(record *dataservices.SessionRecord) error {
+	return service.put(record)
+}
+
+func (service *Service) put(record *dataservices.SessionRecord) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket([]byte(BucketName)).Put([]byte(record.SessionID), data)
+	})
+}
+
+// GetByID returns the record for sessionID, or dataservices.ErrObjectNotFound if none exists.
+func (service *Service) GetByID(sessionID string) (*dataservices.SessionRecord, error) {
+	var record dataservices.SessionRecord
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket([]byte(BucketName)).Get([]byte(sessionID))
+		if data == nil {
+			return dataservices.ErrObjectNotFound
+		}
+
+		return json.Unmarshal(data, &record)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// ListForUser returns every session belonging to userID, most recently created first.
+func (service *Service) ListForUser(userID portainer.UserID) ([]*dataservices.SessionRecord, error) {
+	var records []*dataservices.SessionRecord
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(BucketName)).ForEach(func(k, v []byte) error {
+			var record dataservices.SessionRecord
+			if err := json.Unmarshal(v, &record); err != nil {
+				return err
+			}
+
+			if record.UserID == userID {
+				records = append(records, &record)
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].CreatedAt.After(records[j].CreatedAt)
+	})
+
+	return records, nil
+}
+
+// Terminate marks the session for sessionID as terminated, rejecting any token carrying it from
+// then on regardless of its exp claim.
+func (service *Service) Terminate(sessionID string) error {
+	record, err := service.GetByID(sessionID)
+	if err != nil {
+		return err
+	}
+
+	record.Terminated = true
+
+	return service.Update(record)
+}
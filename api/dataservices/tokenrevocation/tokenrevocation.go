@@ -0,0 +1,117 @@
+package tokenrevocation
+
+import (
+	"encoding/json"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/dataservices"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// BucketName is the bbolt bucket backing this store, one row per jti.
+const BucketName = "token_revocations"
+
+// Service is the bbolt-backed dataservices.TokenRevocationService implementation.
+type Service struct {
+	db *bolt.DB
+}
+
+// NewService creates a Service backed by db, creating BucketName if it doesn't already exist.
+func NewService(db *bolt.DB) (*Service, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists([]byte(BucketName))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{db: db}, nil
+}
+
+// Create persists record, keyed by its JTI.
+func (service *Service) Create(record *dataservices.TokenRevocationRecord) error {
+	return service.put(record)
+}
+
+// Update overwrites the stored record for record.JTI.
+func (service *Service) Update(record *dataservices.TokenRevocationRecord) error {
+	return service.put(record)
+}
+
+func (service *Service) put(record *dataservices.TokenRevocationRecord) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket([]byte(BucketName)).Put([]byte(record.JTI), data)
+	})
+}
+
+// Delete removes the record for jti, if any.
+func (service *Service) Delete(jti string) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(BucketName)).Delete([]byte(jti))
+	})
+}
+
+// GetByJTI returns the record for jti, or dataservices.ErrObjectNotFound if none exists.
+func (service *Service) GetByJTI(jti string) (*dataservices.TokenRevocationRecord, error) {
+	var record dataservices.TokenRevocationRecord
+
+	err := service.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket([]byte(BucketName)).Get([]byte(jti))
+		if data == nil {
+			return dataservices.ErrObjectNotFound
+		}
+
+		return json.Unmarshal(data, &record)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &record, nil
+}
+
+// Revoke marks the record for jti as revoked.
+func (service *Service) Revoke(jti string) error {
+	record, err := service.GetByJTI(jti)
+	if err != nil {
+		return err
+	}
+
+	record.Revoked = true
+
+	return service.Update(record)
+}
+
+// RevokeAllForUser marks every record belonging to userID as revoked.
+func (service *Service) RevokeAllForUser(userID portainer.UserID) error {
+	return service.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(BucketName))
+
+		return bucket.ForEach(func(k, v []byte) error {
+			var record dataservices.TokenRevocationRecord
+			if err := json.Unmarshal(v, &record); err != nil {
+				return err
+			}
+
+			if record.UserID != userID {
+				return nil
+			}
+
+			record.Revoked = true
+
+			data, err := json.Marshal(&record)
+			if err != nil {
+				return err
+			}
+
+			return bucket.Put(k, data)
+		})
+	})
+}
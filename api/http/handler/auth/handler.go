@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/portainer/portainer/api/jwt"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler exposes the refresh/revoke half of the token lifecycle: exchanging a refresh token for a
+// new token pair, and letting a caller revoke the token it is currently presenting (logout). It is
+// mounted by the API router under /api/auth.
+type Handler struct {
+	*mux.Router
+	jwtService *jwt.Service
+}
+
+// NewHandler creates a handler to serve the refresh/revoke API.
+func NewHandler(jwtService *jwt.Service) *Handler {
+	h := &Handler{
+		Router:     mux.NewRouter(),
+		jwtService: jwtService,
+	}
+
+	h.Handle("/auth/refresh", http.HandlerFunc(h.refresh)).Methods(http.MethodPost)
+	h.Handle("/auth/revoke", http.HandlerFunc(h.revoke)).Methods(http.MethodPost)
+
+	return h
+}
+
+type refreshPayload struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+type tokenPairResponse struct {
+	AccessToken  string `json:"accessToken"`
+	RefreshToken string `json:"refreshToken"`
+}
+
+func (handler *Handler) refresh(w http.ResponseWriter, r *http.Request) {
+	var payload refreshPayload
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil || payload.RefreshToken == "" {
+		http.Error(w, "Invalid request payload", http.StatusBadRequest)
+		return
+	}
+
+	access, refresh, err := handler.jwtService.RefreshToken(payload.RefreshToken)
+	if err != nil {
+		http.Error(w, "Invalid or expired refresh token", http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tokenPairResponse{AccessToken: access, RefreshToken: refresh})
+}
+
+func (handler *Handler) revoke(w http.ResponseWriter, r *http.Request) {
+	token := bearerToken(r)
+	if token == "" {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if err := handler.jwtService.LogoutToken(token); err != nil {
+		http.Error(w, "Unable to revoke token", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func bearerToken(r *http.Request) string {
+	const prefix = "Bearer "
+
+	header := r.Header.Get("Authorization")
+	if len(header) <= len(prefix) || header[:len(prefix)] != prefix {
+		return ""
+	}
+
+	return header[len(prefix):]
+}
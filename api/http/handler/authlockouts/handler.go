@@ -0,0 +1,54 @@
+package authlockouts
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/portainer/portainer/api/jwt"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler exposes the brute-force lockout admin API: listing current lockouts and clearing one
+// before its backoff expires. It is mounted by the API router under /api/auth_lockouts, and every
+// route is gated behind jwt.Service.RequireAdmin: without it, the very client being rate-limited
+// could clear its own lockout with one unauthenticated request, defeating the brute-force protection.
+type Handler struct {
+	*mux.Router
+	jwtService *jwt.Service
+}
+
+// NewHandler creates a handler to serve the lockout admin API.
+func NewHandler(jwtService *jwt.Service) *Handler {
+	h := &Handler{
+		Router:     mux.NewRouter(),
+		jwtService: jwtService,
+	}
+
+	h.Handle("/auth_lockouts", jwtService.RequireAdmin(http.HandlerFunc(h.lockoutList))).Methods(http.MethodGet)
+	h.Handle("/auth_lockouts/{key}", jwtService.RequireAdmin(http.HandlerFunc(h.lockoutClear))).Methods(http.MethodDelete)
+
+	return h
+}
+
+func (handler *Handler) lockoutList(w http.ResponseWriter, r *http.Request) {
+	lockouts, err := handler.jwtService.ListLockouts()
+	if err != nil {
+		http.Error(w, "Unable to retrieve lockouts", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(lockouts)
+}
+
+func (handler *Handler) lockoutClear(w http.ResponseWriter, r *http.Request) {
+	key := mux.Vars(r)["key"]
+
+	if err := handler.jwtService.ClearLockout(key); err != nil {
+		http.Error(w, "Unable to clear lockout", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
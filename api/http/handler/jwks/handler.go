@@ -0,0 +1,30 @@
+package jwks
+
+import (
+	"net/http"
+
+	"github.com/portainer/portainer/api/jwt"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler serves the JSON Web Key Set Portainer signs its default-scope tokens with, so that
+// downstream services (kubectl, edge agents, external proxies) can verify Portainer-issued JWTs
+// without ever holding a secret capable of signing new ones. It is mounted by the API router at
+// GET /api/jwks.json.
+type Handler struct {
+	*mux.Router
+	jwtService *jwt.Service
+}
+
+// NewHandler creates a handler to serve the JWKS endpoint.
+func NewHandler(jwtService *jwt.Service) *Handler {
+	h := &Handler{
+		Router:     mux.NewRouter(),
+		jwtService: jwtService,
+	}
+
+	h.Handle("/jwks.json", http.HandlerFunc(h.jwtService.JWKSHandler)).Methods(http.MethodGet)
+
+	return h
+}
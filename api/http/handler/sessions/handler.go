@@ -0,0 +1,65 @@
+package sessions
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	portainer "github.com/portainer/portainer/api"
+	"github.com/portainer/portainer/api/jwt"
+
+	"github.com/gorilla/mux"
+)
+
+// Handler exposes the session management API: listing every session on record for a user
+// (terminated ones included, flagged by Terminated) and terminating a single session by its id
+// (signing a single device out without affecting the user's other sessions). Termination does not
+// check that the session belongs to the user named by the {id} route variable. It is
+// mounted by the API router under /api/users/{id}/sessions, and every route is gated behind
+// jwt.Service.RequireAdmin: without it, any unauthenticated caller could enumerate or terminate
+// another user's sessions just by guessing a numeric id.
+type Handler struct {
+	*mux.Router
+	jwtService *jwt.Service
+}
+
+// NewHandler creates a handler to serve the session management API.
+func NewHandler(jwtService *jwt.Service) *Handler {
+	h := &Handler{
+		Router:     mux.NewRouter(),
+		jwtService: jwtService,
+	}
+
+	h.Handle("/users/{id}/sessions", jwtService.RequireAdmin(http.HandlerFunc(h.sessionList))).Methods(http.MethodGet)
+	h.Handle("/users/{id}/sessions/{sessionId}", jwtService.RequireAdmin(http.HandlerFunc(h.sessionTerminate))).Methods(http.MethodDelete)
+
+	return h
+}
+
+func (handler *Handler) sessionList(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(mux.Vars(r)["id"])
+	if err != nil {
+		http.Error(w, "Invalid user identifier route variable", http.StatusBadRequest)
+		return
+	}
+
+	sessions, err := handler.jwtService.ListSessions(portainer.UserID(id))
+	if err != nil {
+		http.Error(w, "Unable to retrieve sessions", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(sessions)
+}
+
+func (handler *Handler) sessionTerminate(w http.ResponseWriter, r *http.Request) {
+	sessionID := mux.Vars(r)["sessionId"]
+
+	if err := handler.jwtService.TerminateSession(sessionID); err != nil {
+		http.Error(w, "Unable to terminate session", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,83 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+)
+
+// jwk is the subset of RFC 7517 fields Portainer needs to publish: enough for a verifier to
+// reconstruct the public key for the kty it names.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+// JWKSHandler serves the public half of every RSA/ECDSA key currently valid for verifying
+// default-scope tokens, in JWK Set format, so that downstream services (kubectl, edge agents,
+// external proxies) can verify Portainer-issued tokens without sharing a symmetric secret. It is
+// wired up by the API router as GET /api/jwks.json.
+func (service *Service) JWKSHandler(w http.ResponseWriter, r *http.Request) {
+	set := jwks{Keys: []jwk{}}
+
+	for kid, signer := range service.PublicKeys() {
+		key, ok := toJWK(kid, signer)
+		if ok {
+			set.Keys = append(set.Keys, key)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(set)
+}
+
+func toJWK(kid string, signer Signer) (jwk, bool) {
+	switch pub := signer.VerifyKey().(type) {
+	case *rsa.PublicKey:
+		return jwk{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: string(RS256),
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(bigEndianUint(pub.E)),
+		}, true
+	case *ecdsa.PublicKey:
+		return jwk{
+			Kty: "EC",
+			Kid: kid,
+			Use: "sig",
+			Alg: string(ES256),
+			Crv: pub.Curve.Params().Name,
+			X:   base64.RawURLEncoding.EncodeToString(pub.X.Bytes()),
+			Y:   base64.RawURLEncoding.EncodeToString(pub.Y.Bytes()),
+		}, true
+	default:
+		return jwk{}, false
+	}
+}
+
+// bigEndianUint encodes a small positive int (the RSA public exponent) as minimal big-endian bytes.
+func bigEndianUint(v int) []byte {
+	b := []byte{byte(v >> 16), byte(v >> 8), byte(v)}
+
+	i := 0
+	for i < len(b)-1 && b[i] == 0 {
+		i++
+	}
+
+	return b[i:]
+}
@@ -3,6 +3,9 @@ package jwt
 import (
 	"errors"
 	"fmt"
+	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	portainer "github.com/portainer/portainer/api"
@@ -10,33 +13,86 @@ import (
 	"github.com/portainer/portainer/api/dataservices"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 )
 
 const year = time.Hour * 24 * 365
 
+// defaultAccessTokenDuration is the lifetime of an access token minted as part of a token pair.
+// It is intentionally short-lived; long-lived sessions are carried by the refresh token instead.
+const defaultAccessTokenDuration = 15 * time.Minute
+
+// tokenLastUsedWriteThrottle bounds how often parseAndVerifyClaims persists a token's LastUsedAt.
+// Every parse already costs a User().Read, a Session().GetByID and a TokenRevocation().GetByJTI
+// against the datastore; without this throttle it would also issue a TokenRevocation().Update on
+// every single authenticated request (e.g. every poll the UI makes), which is a needless write
+// amplification against the embedded bbolt store. Idle-timeout accuracy only needs minute
+// resolution, not per-request resolution, so writes are coalesced to once per throttle window.
+const tokenLastUsedWriteThrottle = time.Minute
+
 // scope represents JWT scopes that are supported in JWT claims.
 type scope string
 
+// tokenType distinguishes access tokens from refresh tokens in the claims, since both are
+// JWTs signed with the same key but carry different validity and renewal semantics.
+type tokenType string
+
+const (
+	accessToken  = tokenType("access")
+	refreshToken = tokenType("refresh")
+)
+
 // Service represents a service for managing JWT tokens.
 type Service struct {
-	secrets            map[scope][]byte
+	// keyringsMu guards keyrings, which is read on every token mint/parse and written both by
+	// RotateSigningKey (invoked on a schedule, concurrently with request handling) and by
+	// keyringFor the first time a newly registered scope is used.
+	keyringsMu         sync.RWMutex
+	keyrings           map[scope]*keyring
+	scopes             *ScopeRegistry
+	signingAlgorithm   Algorithm
 	userSessionTimeout time.Duration
+	tokenIdleTimeout   time.Duration
+	maxAuthFailures    int
+	authFailureWindow  time.Duration
 	dataStore          dataservices.DataStore
 }
 
 type claims struct {
-	UserID              int    `json:"id"`
-	Username            string `json:"username"`
-	Role                int    `json:"role"`
-	Scope               scope  `json:"scope"`
-	ForceChangePassword bool   `json:"forceChangePassword"`
+	UserID              int            `json:"id"`
+	Username            string         `json:"username"`
+	Role                int            `json:"role"`
+	Scope               scope          `json:"scope"`
+	ForceChangePassword bool           `json:"forceChangePassword"`
+	TokenType           tokenType      `json:"tokenType,omitempty"`
+	DeviceID            string         `json:"deviceId,omitempty"`
+	SessionID           string         `json:"sessionId,omitempty"`
+	IP                  string         `json:"ip,omitempty"`
+	UserAgent           string         `json:"userAgent,omitempty"`
+	Extra               map[string]any `json:"extra,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// TokenOptions carries the device/session fingerprint that GenerateToken and GenerateTokenPair
+// stamp onto the issued token's claims and persist in the session store. It lets administrators
+// (and end users) later list and terminate individual devices instead of signing out everywhere.
+type TokenOptions struct {
+	DeviceID  string
+	IP        string
+	UserAgent string
+}
+
 var (
-	errSecretGeneration = errors.New("Unable to generate secret key")
-	errInvalidJWTToken  = errors.New("Invalid JWT token")
+	errSecretGeneration  = errors.New("Unable to generate secret key")
+	errInvalidJWTToken   = errors.New("Invalid JWT token")
+	errTokenIdle         = errors.New("Token has been idle for too long")
+	errTokenRevoked      = errors.New("Token has been revoked")
+	errUnknownKID        = errors.New("Unknown signing key id")
+	errAlgMismatch       = errors.New("Token algorithm does not match its signing key")
+	errSessionTerminated = errors.New("Session has been terminated")
+	errUnknownScope      = errors.New("Unknown JWT scope")
+	errWrongTokenType    = errors.New("Token's type does not match the use it was presented for")
 )
 
 const (
@@ -44,16 +100,56 @@ const (
 	kubeConfigScope = scope("kubeconfig")
 )
 
-// NewService initializes a new service. It will generate a random key that will be used to sign JWT tokens.
-func NewService(userSessionDuration string, dataStore dataservices.DataStore) (*Service, error) {
+// defaultTokenIdleTimeout is used unless overridden via WithTokenIdleTimeout.
+const defaultTokenIdleTimeout = 24 * time.Hour
+
+// ServiceOption configures optional Service behavior at construction time. Keeping these as
+// options rather than positional NewService parameters means adding a new knob never breaks an
+// existing call site the way a new required parameter would.
+type ServiceOption func(*Service)
+
+// WithTokenIdleTimeout overrides the default token idle timeout (24h): any token unused for
+// longer than this is rejected by ParseAndVerifyToken even if its JWT exp has not yet passed.
+func WithTokenIdleTimeout(idleTimeout time.Duration) ServiceOption {
+	return func(service *Service) {
+		service.tokenIdleTimeout = idleTimeout
+	}
+}
+
+// WithSigningAlgorithm overrides the default signing algorithm (HS256) used for the default scope.
+func WithSigningAlgorithm(algorithm Algorithm) ServiceOption {
+	return func(service *Service) {
+		service.signingAlgorithm = algorithm
+	}
+}
+
+// NewService initializes a new service. It loads the default scope's signing keys from the
+// datastore keyring, generating and persisting a new random key of the selected algorithm (HS256
+// unless WithSigningAlgorithm says otherwise) when none exists or the newest is older than
+// keyRotationInterval.
+func NewService(userSessionDuration string, dataStore dataservices.DataStore, opts ...ServiceOption) (*Service, error) {
 	userSessionTimeout, err := time.ParseDuration(userSessionDuration)
 	if err != nil {
 		return nil, err
 	}
 
-	secret := apikey.GenerateRandomKey(32)
-	if secret == nil {
-		return nil, errSecretGeneration
+	service := &Service{
+		scopes:             newScopeRegistry(),
+		signingAlgorithm:   HS256,
+		userSessionTimeout: userSessionTimeout,
+		tokenIdleTimeout:   defaultTokenIdleTimeout,
+		maxAuthFailures:    defaultMaxAuthFailures,
+		authFailureWindow:  defaultAuthFailureWindow,
+		dataStore:          dataStore,
+	}
+
+	for _, opt := range opts {
+		opt(service)
+	}
+
+	defaultKeyring, err := currentSigningKeyring(dataStore, defaultScope, service.signingAlgorithm)
+	if err != nil {
+		return nil, err
 	}
 
 	kubeSecret, err := getOrCreateKubeSecret(dataStore)
@@ -61,15 +157,20 @@ func NewService(userSessionDuration string, dataStore dataservices.DataStore) (*
 		return nil, err
 	}
 
-	service := &Service{
-		map[scope][]byte{
-			defaultScope:    secret,
-			kubeConfigScope: kubeSecret,
-		},
-		userSessionTimeout,
-		dataStore,
+	kubeSigner := NewHMACSigner("kubeconfig", kubeSecret)
+	kubeKeyring := &keyring{
+		current: kubeSigner,
+		byKID:   map[string]Signer{kubeSigner.KID(): kubeSigner},
+	}
+
+	service.keyrings = map[scope]*keyring{
+		defaultScope:    defaultKeyring,
+		kubeConfigScope: kubeKeyring,
 	}
 
+	service.RegisterScope(ScopeDescriptor{Name: string(defaultScope), Algorithm: service.signingAlgorithm, TTL: userSessionTimeout})
+	service.RegisterScope(ScopeDescriptor{Name: string(kubeConfigScope), Algorithm: HS256})
+
 	return service, nil
 }
 
@@ -100,34 +201,432 @@ func (service *Service) defaultExpireAt() time.Time {
 	return time.Now().Add(service.userSessionTimeout)
 }
 
-// GenerateToken generates a new JWT token.
-func (service *Service) GenerateToken(data *portainer.TokenData) (string, time.Time, error) {
+// RotateSigningKey mints a new signing key for the default scope and makes it the one used to sign
+// new tokens going forward, while keeping the previous keys around for verification. It is intended
+// to be invoked on a schedule (e.g. every keyRotationInterval) by a background job; see StartKeyRotation.
+func (service *Service) RotateSigningKey() error {
+	if _, err := rotateSigningKey(service.dataStore, defaultScope, service.signingAlgorithm); err != nil {
+		return err
+	}
+
+	kr, err := currentSigningKeyring(service.dataStore, defaultScope, service.signingAlgorithm)
+	if err != nil {
+		return err
+	}
+
+	service.keyringsMu.Lock()
+	service.keyrings[defaultScope] = kr
+	service.keyringsMu.Unlock()
+
+	return nil
+}
+
+// StartKeyRotation runs RotateSigningKey every keyRotationInterval in its own goroutine, until stop
+// is closed. currentSigningKeyring already rotates lazily the first time a scope's keyring is
+// populated, but a long-running process that never restarts would otherwise never pick up a fresh
+// key after that; this is the background job RotateSigningKey's doc comment describes. The caller
+// owns stop's lifetime and should close it as part of the service's own shutdown.
+func (service *Service) StartKeyRotation(stop <-chan struct{}) {
+	go func() {
+		ticker := time.NewTicker(keyRotationInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := service.RotateSigningKey(); err != nil {
+					log.Warn().Err(err).Msg("failed rotating JWT signing key")
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// keyringFor returns the keyring backing s, lazily creating and caching one using the scope's
+// registered algorithm the first time a token for a newly registered scope is minted or parsed.
+func (service *Service) keyringFor(s scope) (*keyring, error) {
+	service.keyringsMu.RLock()
+	kr, found := service.keyrings[s]
+	service.keyringsMu.RUnlock()
+	if found {
+		return kr, nil
+	}
+
+	descriptor, found := service.scopes.get(s)
+	if !found {
+		return nil, errUnknownScope
+	}
+
+	kr, err := currentSigningKeyring(service.dataStore, s, descriptor.Algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	service.keyringsMu.Lock()
+	service.keyrings[s] = kr
+	service.keyringsMu.Unlock()
+
+	return kr, nil
+}
+
+// PublicKeys returns the RSA/ECDSA public keys currently valid for verifying default-scope tokens,
+// indexed by kid. HMAC keys are never returned since they are symmetric secret material.
+func (service *Service) PublicKeys() map[string]Signer {
+	service.keyringsMu.RLock()
+	defer service.keyringsMu.RUnlock()
+
+	keys := map[string]Signer{}
+
+	for kid, signer := range service.keyrings[defaultScope].byKID {
+		if signer.Algorithm() != HS256 {
+			keys[kid] = signer
+		}
+	}
+
+	return keys
+}
+
+// firstTokenOptions returns opts[0], or the zero value if opts is empty. opts is variadic purely
+// so that adding TokenOptions to GenerateToken/GenerateTokenPair didn't break existing call sites
+// that predate it.
+func firstTokenOptions(opts []TokenOptions) TokenOptions {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+
+	return TokenOptions{}
+}
+
+// GenerateToken generates a new, standalone JWT token in the default scope: no refresh token, one
+// long-lived access token valid for the full userSessionTimeout. opts is optional and carries the
+// device/session fingerprint to stamp onto the token; omit it for call sites that predate
+// TokenOptions. Like GenerateTokenPair, it creates a TokenRevocationRecord for the token's jti so
+// that RevokeToken/RevokeAllForUser and the idle timeout actually apply to it; without one,
+// parseAndVerifyClaims has no record to check and silently treats the token as never idle and
+// never revoked.
+func (service *Service) GenerateToken(data *portainer.TokenData, opts ...TokenOptions) (string, time.Time, error) {
 	expiryTime := service.defaultExpireAt()
-	token, err := service.generateSignedToken(data, expiryTime, defaultScope)
-	return token, expiryTime, err
+	jti := uuid.NewString()
+
+	options := firstTokenOptions(opts)
+
+	token, err := service.generateSignedToken(data, expiryTime, defaultScope, accessToken, jti, jti, options, nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	if err := service.dataStore.TokenRevocation().Create(&dataservices.TokenRevocationRecord{
+		JTI:        jti,
+		UserID:     data.ID,
+		LastUsedAt: time.Now(),
+	}); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed tracking token: %w", err)
+	}
+
+	if err := service.dataStore.Session().Create(&dataservices.SessionRecord{
+		SessionID:  jti,
+		UserID:     data.ID,
+		DeviceID:   options.DeviceID,
+		IP:         options.IP,
+		UserAgent:  options.UserAgent,
+		CreatedAt:  time.Now(),
+		LastSeenAt: time.Now(),
+	}); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed tracking session: %w", err)
+	}
+
+	return token, expiryTime, nil
+}
+
+// GenerateScopedToken mints a token for scopeName, carrying data plus extraClaims. scopeName must
+// have been registered via RegisterScope; its TTL and signing algorithm drive how long the token
+// lives and how it's signed, so callers no longer need jwt.go to hardcode their scope's policy.
+func (service *Service) GenerateScopedToken(scopeName string, data *portainer.TokenData, extraClaims map[string]any) (string, time.Time, error) {
+	descriptor, found := service.scopes.get(scope(scopeName))
+	if !found {
+		return "", time.Time{}, errUnknownScope
+	}
+
+	var expiresAt time.Time
+	if descriptor.TTL > 0 {
+		expiresAt = time.Now().Add(descriptor.TTL)
+	}
+
+	jti := uuid.NewString()
+	token, err := service.generateSignedToken(data, expiresAt, scope(scopeName), accessToken, jti, jti, TokenOptions{}, extraClaims)
+	return token, expiresAt, err
+}
+
+// GenerateTokenPair issues a short-lived access token alongside a longer-lived refresh token. The
+// refresh token's lifetime is the configured userSessionTimeout, while the access token expires in
+// defaultAccessTokenDuration. opts is optional for the same reason as in GenerateToken.
+func (service *Service) GenerateTokenPair(data *portainer.TokenData, opts ...TokenOptions) (access, refresh string, err error) {
+	return service.issueTokenPair(data, uuid.NewString(), firstTokenOptions(opts), true)
+}
+
+// issueTokenPair signs a fresh access/refresh pair for data under sessionID. jti (the pair's own
+// revocation identifier) always rotates, but sessionID is caller-controlled: a brand new login
+// passes a freshly minted sessionID and newSession=true to create its SessionRecord, while a
+// refresh passes the session's existing sessionID and newSession=false to update that same record
+// in place instead of leaving behind an ever-growing trail of abandoned sessions every time the
+// short-lived access token is renewed.
+func (service *Service) issueTokenPair(data *portainer.TokenData, sessionID string, opts TokenOptions, newSession bool) (access, refresh string, err error) {
+	jti := uuid.NewString()
+
+	access, err = service.generateSignedToken(data, time.Now().Add(defaultAccessTokenDuration), defaultScope, accessToken, jti, sessionID, opts, nil)
+	if err != nil {
+		return "", "", err
+	}
+
+	refresh, err = service.generateSignedToken(data, service.defaultExpireAt(), defaultScope, refreshToken, jti, sessionID, opts, nil)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := service.dataStore.TokenRevocation().Create(&dataservices.TokenRevocationRecord{
+		JTI:        jti,
+		UserID:     data.ID,
+		LastUsedAt: time.Now(),
+	}); err != nil {
+		return "", "", fmt.Errorf("failed tracking refresh token: %w", err)
+	}
+
+	if newSession {
+		if err := service.dataStore.Session().Create(&dataservices.SessionRecord{
+			SessionID:  sessionID,
+			UserID:     data.ID,
+			DeviceID:   opts.DeviceID,
+			IP:         opts.IP,
+			UserAgent:  opts.UserAgent,
+			CreatedAt:  time.Now(),
+			LastSeenAt: time.Now(),
+		}); err != nil {
+			return "", "", fmt.Errorf("failed tracking session: %w", err)
+		}
+
+		return access, refresh, nil
+	}
+
+	session, err := service.dataStore.Session().GetByID(sessionID)
+	if err != nil || session == nil {
+		session = &dataservices.SessionRecord{SessionID: sessionID, UserID: data.ID, CreatedAt: time.Now()}
+	}
+
+	session.DeviceID = opts.DeviceID
+	session.IP = opts.IP
+	session.UserAgent = opts.UserAgent
+	session.LastSeenAt = time.Now()
+
+	if err := service.dataStore.Session().Update(session); err != nil {
+		return "", "", fmt.Errorf("failed updating session: %w", err)
+	}
+
+	return access, refresh, nil
+}
+
+// RefreshToken exchanges a valid, non-revoked refresh token for a new token pair. The old refresh
+// token's jti is revoked so it cannot be replayed once it has been used to refresh, but the
+// session itself (and its SessionRecord) carries over unchanged so that ListSessions keeps
+// reflecting one row per login rather than one row per refresh.
+func (service *Service) RefreshToken(refresh string) (newAccess, newRefresh string, err error) {
+	data, cl, err := service.parseAndVerifyClaims(refresh, refreshToken)
+	if err != nil {
+		return "", "", err
+	}
+
+	sessionID := cl.SessionID
+	if sessionID == "" {
+		sessionID = cl.ID
+	}
+
+	newAccess, newRefresh, err = service.issueTokenPair(data, sessionID, TokenOptions{DeviceID: cl.DeviceID, IP: cl.IP, UserAgent: cl.UserAgent}, false)
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := service.dataStore.TokenRevocation().Revoke(cl.ID); err != nil {
+		return "", "", fmt.Errorf("failed rotating refresh token: %w", err)
+	}
+
+	return newAccess, newRefresh, nil
+}
+
+// ListSessions returns every session on record for userID, most recently created first, so that
+// the user (or an administrator) can see what's signed in before deciding what to terminate.
+func (service *Service) ListSessions(userID portainer.UserID) ([]*dataservices.SessionRecord, error) {
+	return service.dataStore.Session().ListForUser(userID)
+}
+
+// TerminateSession kills a single session by id. ParseAndVerifyToken rejects any token carrying
+// that session id from then on, regardless of its exp claim, letting a user sign a single device out.
+func (service *Service) TerminateSession(sessionID string) error {
+	return service.dataStore.Session().Terminate(sessionID)
+}
+
+// RevokeToken revokes the refresh token (and any access token sharing its jti) identified by jti. Once
+// revoked, ParseAndVerifyToken will reject any token carrying that jti regardless of its exp claim.
+func (service *Service) RevokeToken(jti string) error {
+	return service.dataStore.TokenRevocation().Revoke(jti)
+}
+
+// RevokeAllForUser revokes every outstanding refresh token belonging to userID, signing the user out of
+// every device that is relying on silent token refresh.
+func (service *Service) RevokeAllForUser(userID portainer.UserID) error {
+	return service.dataStore.TokenRevocation().RevokeAllForUser(userID)
+}
+
+// LogoutToken revokes the jti carried by token, as a caller logging itself out of its own session.
+// It derives the jti from the token's own (verified) claims rather than trusting a caller-supplied
+// jti, so a caller can only ever revoke the token it is presenting, never an arbitrary one.
+func (service *Service) LogoutToken(token string) error {
+	_, cl, err := service.parseAndVerifyClaims(token, accessToken)
+	if err != nil {
+		return err
+	}
+
+	return service.RevokeToken(cl.ID)
 }
 
 // ParseAndVerifyToken parses a JWT token and verify its validity. It returns an error if token is invalid.
+// Only access tokens are accepted here: a refresh token (which lives far longer than an access
+// token and is only meant to be exchanged via RefreshToken) must not authenticate ordinary requests.
 func (service *Service) ParseAndVerifyToken(token string) (*portainer.TokenData, error) {
+	data, _, err := service.parseAndVerifyClaims(token, accessToken)
+	return data, err
+}
+
+// RequireAdmin wraps next so that it only runs for requests bearing a valid, non-expired access
+// token belonging to an administrator, rejecting everything else with 401/403 before next ever
+// runs. Admin-only HTTP surfaces built directly on top of Service (session management, lockout
+// management) use this instead of each reimplementing bearer-token parsing and role checks.
+func (service *Service) RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := bearerToken(r)
+		if token == "" {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		data, err := service.ParseAndVerifyToken(token)
+		if err != nil {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		if data.Role != portainer.AdministratorRole {
+			http.Error(w, "Access denied", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func bearerToken(r *http.Request) string {
+	const prefix = "Bearer "
+
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, prefix) {
+		return ""
+	}
+
+	return strings.TrimPrefix(header, prefix)
+}
+
+// parseAndVerifyClaims verifies the token's signature and claims, rejecting it unless its
+// TokenType matches expectedType (a token with no TokenType at all is let through, for tokens
+// issued before that claim existed). Beyond signature verification, a successful parse costs up to
+// three datastore reads (User().Read, Session().GetByID, TokenRevocation().GetByJTI) and, at most
+// once per tokenLastUsedWriteThrottle, a TokenRevocation().Update write. Callers that need to
+// verify tokens on every request (e.g. the UI's polling) inherit that cost; it is no longer the
+// single stateless signature check it used to be before idle-timeout and session revocation were
+// introduced.
+func (service *Service) parseAndVerifyClaims(token string, expectedType tokenType) (*portainer.TokenData, *claims, error) {
 	scope := parseScope(token)
-	secret := service.secrets[scope]
+
+	descriptor, found := service.scopes.get(scope)
+	if !found {
+		return nil, nil, errUnknownScope
+	}
+
+	kr, err := service.keyringFor(scope)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kid, alg := parseKeyHeader(token)
+
+	signer, found := kr.byKID[kid]
+	if !found {
+		return nil, nil, errUnknownKID
+	}
+
+	if alg != string(signer.Algorithm()) {
+		return nil, nil, errAlgMismatch
+	}
+
 	parsedToken, err := jwt.ParseWithClaims(token, &claims{}, func(token *jwt.Token) (any, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			msg := fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			return nil, msg
+		if token.Method.Alg() != signer.SigningMethod().Alg() {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return secret, nil
+		return signer.VerifyKey(), nil
 	})
 
 	if err == nil && parsedToken != nil {
 		if cl, ok := parsedToken.Claims.(*claims); ok && parsedToken.Valid {
 
+			if cl.TokenType != "" && cl.TokenType != expectedType {
+				return nil, nil, errWrongTokenType
+			}
+
 			user, err := service.dataStore.User().Read(portainer.UserID(cl.UserID))
 			if err != nil {
-				return nil, errInvalidJWTToken
+				return nil, nil, errInvalidJWTToken
 			}
 			if user.TokenIssueAt > cl.RegisteredClaims.IssuedAt.Unix() {
-				return nil, errInvalidJWTToken
+				return nil, nil, errInvalidJWTToken
+			}
+
+			if cl.SessionID != "" {
+				session, err := service.dataStore.Session().GetByID(cl.SessionID)
+				if err != nil && !dataservices.IsErrObjectNotFound(err) {
+					return nil, nil, fmt.Errorf("failed reading session: %w", err)
+				}
+				if session != nil && session.Terminated {
+					return nil, nil, errSessionTerminated
+				}
+			}
+
+			if cl.ID != "" {
+				record, err := service.dataStore.TokenRevocation().GetByJTI(cl.ID)
+				if err != nil && !dataservices.IsErrObjectNotFound(err) {
+					return nil, nil, fmt.Errorf("failed reading token revocation record: %w", err)
+				}
+
+				if record != nil {
+					if record.Revoked {
+						return nil, nil, errTokenRevoked
+					}
+
+					if time.Since(record.LastUsedAt) > service.tokenIdleTimeout {
+						return nil, nil, errTokenIdle
+					}
+
+					if time.Since(record.LastUsedAt) >= tokenLastUsedWriteThrottle {
+						record.LastUsedAt = time.Now()
+						if err := service.dataStore.TokenRevocation().Update(record); err != nil {
+							log.Warn().Err(err).Msg("failed updating token last-used timestamp")
+						}
+					}
+				}
+			}
+
+			if descriptor.RequiredClaims != nil {
+				if err := descriptor.RequiredClaims(cl.Extra); err != nil {
+					return nil, nil, fmt.Errorf("invalid claims for scope %s: %w", scope, err)
+				}
 			}
 
 			return &portainer.TokenData{
@@ -136,37 +635,58 @@ func (service *Service) ParseAndVerifyToken(token string) (*portainer.TokenData,
 				Role:                portainer.UserRole(cl.Role),
 				Token:               token,
 				ForceChangePassword: cl.ForceChangePassword,
-			}, nil
+			}, cl, nil
 		}
 	}
-	return nil, errInvalidJWTToken
+	return nil, nil, errInvalidJWTToken
 }
 
-// parse a JWT token, fallback to defaultScope if no scope is present in the JWT
+// parse a JWT token's scope claim, falling back to defaultScope if none is present. Unlike the
+// old hardcoded kubeConfigScope check, any scope name is accepted here; whether it's actually
+// known is decided by the ScopeRegistry in parseAndVerifyClaims.
 func parseScope(token string) scope {
 	unverifiedToken, _, _ := new(jwt.Parser).ParseUnverified(token, &claims{})
 	if unverifiedToken != nil {
-		if cl, ok := unverifiedToken.Claims.(*claims); ok {
-			if cl.Scope == kubeConfigScope {
-				return kubeConfigScope
-			}
+		if cl, ok := unverifiedToken.Claims.(*claims); ok && cl.Scope != "" {
+			return cl.Scope
 		}
 	}
 
 	return defaultScope
 }
 
+// parseKeyHeader extracts the kid and alg header values from a token without verifying its
+// signature, so that the matching Signer can be looked up before verification is attempted.
+func parseKeyHeader(token string) (kid, alg string) {
+	unverifiedToken, _, _ := new(jwt.Parser).ParseUnverified(token, &claims{})
+	if unverifiedToken == nil {
+		return "", ""
+	}
+
+	if v, ok := unverifiedToken.Header["kid"].(string); ok {
+		kid = v
+	}
+
+	if v, ok := unverifiedToken.Header["alg"].(string); ok {
+		alg = v
+	}
+
+	return kid, alg
+}
+
 // SetUserSessionDuration sets the user session duration
 func (service *Service) SetUserSessionDuration(userSessionDuration time.Duration) {
 	service.userSessionTimeout = userSessionDuration
 }
 
-func (service *Service) generateSignedToken(data *portainer.TokenData, expiresAt time.Time, scope scope) (string, error) {
-	secret, found := service.secrets[scope]
-	if !found {
-		return "", fmt.Errorf("invalid scope: %v", scope)
+func (service *Service) generateSignedToken(data *portainer.TokenData, expiresAt time.Time, scope scope, tt tokenType, jti, sessionID string, opts TokenOptions, extraClaims map[string]any) (string, error) {
+	kr, err := service.keyringFor(scope)
+	if err != nil {
+		return "", fmt.Errorf("invalid scope: %v: %w", scope, err)
 	}
 
+	signer := kr.current
+
 	settings, err := service.dataStore.Settings().Settings()
 	if err != nil {
 		return "", fmt.Errorf("failed fetching settings from db: %w", err)
@@ -184,7 +704,14 @@ func (service *Service) generateSignedToken(data *portainer.TokenData, expiresAt
 		Role:                int(data.Role),
 		Scope:               scope,
 		ForceChangePassword: data.ForceChangePassword,
+		TokenType:           tt,
+		DeviceID:            opts.DeviceID,
+		SessionID:           sessionID,
+		IP:                  opts.IP,
+		UserAgent:           opts.UserAgent,
+		Extra:               extraClaims,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 		},
@@ -195,8 +722,10 @@ func (service *Service) generateSignedToken(data *portainer.TokenData, expiresAt
 		cl.RegisteredClaims.ExpiresAt = nil
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, cl)
-	signedToken, err := token.SignedString(secret)
+	token := jwt.NewWithClaims(signer.SigningMethod(), cl)
+	token.Header["kid"] = signer.KID()
+
+	signedToken, err := token.SignedString(signer.SignKey())
 	if err != nil {
 		return "", err
 	}
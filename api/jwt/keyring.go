@@ -0,0 +1,182 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"fmt"
+	"time"
+
+	"github.com/portainer/portainer/api/apikey"
+	"github.com/portainer/portainer/api/dataservices"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// keyRotationInterval is how often a new signing key is minted for a scope.
+// keyRetentionCount is how many of the most recent keys remain valid for verification after
+// rotation, so that tokens issued just before a rotation are not rejected before they expire.
+const (
+	keyRotationInterval = 30 * 24 * time.Hour
+	keyRetentionCount   = 3
+)
+
+// keyring holds the Signer currently used to mint new tokens for a scope (current) plus the
+// full set of still-valid signers, indexed by kid, used to verify previously issued tokens.
+type keyring struct {
+	current Signer
+	byKID   map[string]Signer
+}
+
+// currentSigningKeyring loads the keyring for scope from the datastore, rotating in a freshly
+// generated key if none exists yet or the active key is older than keyRotationInterval.
+func currentSigningKeyring(dataStore dataservices.DataStore, scope scope, algorithm Algorithm) (*keyring, error) {
+	entries, err := dataStore.Keyring().AllForScope(string(scope))
+	if err != nil {
+		return nil, fmt.Errorf("failed loading keyring: %w", err)
+	}
+
+	active := newestEntry(entries)
+	if active == nil || time.Since(active.CreatedAt) > keyRotationInterval {
+		active, err = rotateSigningKey(dataStore, scope, algorithm)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, active)
+	}
+
+	kr := &keyring{byKID: map[string]Signer{}}
+
+	for _, entry := range retainLatest(entries, keyRetentionCount) {
+		signer, err := signerFromEntry(entry)
+		if err != nil {
+			log.Warn().Err(err).Str("kid", entry.KID).Msg("skipping unreadable signing key")
+			continue
+		}
+
+		kr.byKID[entry.KID] = signer
+		if entry.KID == active.KID {
+			kr.current = signer
+		}
+	}
+
+	return kr, nil
+}
+
+// rotateSigningKey mints a brand new key for scope, persists it, and returns it. Older keys are
+// left in place so that tokens they signed remain verifiable until they age out of retention.
+func rotateSigningKey(dataStore dataservices.DataStore, scope scope, algorithm Algorithm) (*dataservices.KeyringEntry, error) {
+	kid := uuid.NewString()
+
+	var (
+		entry *dataservices.KeyringEntry
+		err   error
+	)
+
+	switch algorithm {
+	case RS256:
+		var key *rsa.PrivateKey
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, fmt.Errorf("failed generating RSA key: %w", err)
+		}
+
+		entry = &dataservices.KeyringEntry{
+			KID:        kid,
+			Scope:      string(scope),
+			Algorithm:  string(RS256),
+			PrivateKey: x509.MarshalPKCS1PrivateKey(key),
+			CreatedAt:  time.Now(),
+		}
+	case ES256:
+		var key *ecdsa.PrivateKey
+		key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed generating ECDSA key: %w", err)
+		}
+
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed marshalling ECDSA key: %w", err)
+		}
+
+		entry = &dataservices.KeyringEntry{
+			KID:        kid,
+			Scope:      string(scope),
+			Algorithm:  string(ES256),
+			PrivateKey: der,
+			CreatedAt:  time.Now(),
+		}
+	default:
+		secret := apikey.GenerateRandomKey(32)
+		if secret == nil {
+			return nil, errSecretGeneration
+		}
+
+		entry = &dataservices.KeyringEntry{
+			KID:        kid,
+			Scope:      string(scope),
+			Algorithm:  string(HS256),
+			PrivateKey: secret,
+			CreatedAt:  time.Now(),
+		}
+	}
+
+	if err := dataStore.Keyring().Create(entry); err != nil {
+		return nil, fmt.Errorf("failed persisting signing key: %w", err)
+	}
+
+	return entry, nil
+}
+
+func signerFromEntry(entry *dataservices.KeyringEntry) (Signer, error) {
+	switch Algorithm(entry.Algorithm) {
+	case RS256:
+		key, err := x509.ParsePKCS1PrivateKey(entry.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		return NewRSASigner(entry.KID, key), nil
+	case ES256:
+		key, err := x509.ParseECPrivateKey(entry.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		return NewECDSASigner(entry.KID, key), nil
+	default:
+		return NewHMACSigner(entry.KID, entry.PrivateKey), nil
+	}
+}
+
+func newestEntry(entries []*dataservices.KeyringEntry) *dataservices.KeyringEntry {
+	latest := retainLatest(entries, 1)
+	if len(latest) == 0 {
+		return nil
+	}
+
+	return latest[0]
+}
+
+// retainLatest returns the n most recently created entries, newest first.
+func retainLatest(entries []*dataservices.KeyringEntry, n int) []*dataservices.KeyringEntry {
+	sorted := make([]*dataservices.KeyringEntry, len(entries))
+	copy(sorted, entries)
+
+	for i := 0; i < len(sorted); i++ {
+		for j := i + 1; j < len(sorted); j++ {
+			if sorted[j].CreatedAt.After(sorted[i].CreatedAt) {
+				sorted[i], sorted[j] = sorted[j], sorted[i]
+			}
+		}
+	}
+
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+
+	return sorted
+}
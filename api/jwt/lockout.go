@@ -0,0 +1,104 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/portainer/portainer/api/dataservices"
+)
+
+// defaultMaxAuthFailures and defaultAuthFailureWindow implement a 5/30m policy out of the box:
+// five failed attempts inside one 30 minute window trigger a lockout. The window is fixed, not
+// sliding: it starts at the first failure and the count resets once the window has elapsed.
+const (
+	defaultMaxAuthFailures   = 5
+	defaultAuthFailureWindow = 30 * time.Minute
+)
+
+var errAccountLocked = errors.New("Too many failed authentication attempts")
+
+// SetAuthLockoutPolicy overrides the default 5/30m brute-force policy enforced by
+// RecordAuthFailure and CheckAuthAllowed.
+func (service *Service) SetAuthLockoutPolicy(maxFailures int, window time.Duration) {
+	service.maxAuthFailures = maxFailures
+	service.authFailureWindow = window
+}
+
+// RecordAuthFailure records a failed authentication attempt against both the username and the
+// source IP it came from, so that an attacker spreading attempts across many usernames from one
+// IP (or hammering one username from many IPs) is still rate limited either way. Each failure
+// past maxAuthFailures within authFailureWindow extends the lockout with progressive backoff.
+// Neither this package nor this tree owns the login handler that verifies credentials; whatever
+// does owns calling CheckAuthAllowed before verifying a credential and RecordAuthFailure after a
+// failed one. Until that wiring exists, CheckAuthAllowed/RecordAuthFailure are dead code reachable
+// only through ListLockouts/ClearLockout's effects, and this package cannot enforce that on its own.
+func (service *Service) RecordAuthFailure(username, ip string) error {
+	now := time.Now()
+
+	for _, key := range []string{authLockoutKey("user", username), authLockoutKey("ip", ip)} {
+		if err := service.bumpLockout(key, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckAuthAllowed returns errAccountLocked if either username or ip is currently locked out.
+// Authentication handlers should call this before attempting to verify credentials.
+func (service *Service) CheckAuthAllowed(username, ip string) error {
+	for _, key := range []string{authLockoutKey("user", username), authLockoutKey("ip", ip)} {
+		record, err := service.dataStore.AuthLockout().GetByKey(key)
+		if err != nil || record == nil {
+			continue
+		}
+
+		if time.Now().Before(record.LockedUntil) {
+			return fmt.Errorf("%w: locked until %s", errAccountLocked, record.LockedUntil.Format(time.RFC3339))
+		}
+	}
+
+	return nil
+}
+
+// ListLockouts returns every lockout record currently on file, for the admin UI to display.
+func (service *Service) ListLockouts() ([]*dataservices.AuthLockoutRecord, error) {
+	return service.dataStore.AuthLockout().All()
+}
+
+// ClearLockout removes the lockout record for key (as produced by authLockoutKey), immediately
+// allowing authentication attempts again.
+func (service *Service) ClearLockout(key string) error {
+	return service.dataStore.AuthLockout().Delete(key)
+}
+
+func (service *Service) bumpLockout(key string, now time.Time) error {
+	record, err := service.dataStore.AuthLockout().GetByKey(key)
+	if err != nil && !dataservices.IsErrObjectNotFound(err) {
+		return fmt.Errorf("failed reading lockout record: %w", err)
+	}
+
+	if record == nil {
+		record = &dataservices.AuthLockoutRecord{Key: key, WindowStart: now}
+	}
+
+	if now.Sub(record.WindowStart) > service.authFailureWindow {
+		record.WindowStart = now
+		record.FailureCount = 0
+		record.LockedUntil = time.Time{}
+	}
+
+	record.FailureCount++
+
+	if record.FailureCount >= service.maxAuthFailures {
+		backoff := time.Duration(record.FailureCount-service.maxAuthFailures+1) * service.authFailureWindow
+		record.LockedUntil = now.Add(backoff)
+	}
+
+	return service.dataStore.AuthLockout().Update(record)
+}
+
+func authLockoutKey(kind, value string) string {
+	return kind + ":" + value
+}
@@ -0,0 +1,58 @@
+package jwt
+
+import (
+	"sync"
+	"time"
+)
+
+// ScopeDescriptor describes how tokens minted for a given scope are signed, how long they live,
+// and what they must carry. Packages outside jwt register one via Service.RegisterScope before
+// minting or parsing a token in that scope, so that features like edge-compute tokens, one-shot
+// deploy tokens, or webhook-signed callbacks can reuse the JWT plumbing instead of each
+// reinventing signing and each getting hardcoded into this package.
+type ScopeDescriptor struct {
+	// Name identifies the scope in the JWT `scope` claim.
+	Name string
+	// Algorithm selects the signing key family used to mint tokens in this scope.
+	Algorithm Algorithm
+	// TTL is how long a token minted in this scope is valid for via GenerateScopedToken. A zero
+	// TTL means the token never expires.
+	TTL time.Duration
+	// RequiredClaims validates the scope-specific extra claims carried by a token, after its
+	// signature has already been verified. A nil RequiredClaims accepts any extra claims.
+	RequiredClaims func(extra map[string]any) error
+}
+
+// ScopeRegistry holds every scope a running Portainer instance knows how to mint and verify
+// tokens for, keyed by scope name. Register and get are called concurrently with request
+// handling (a package can call RegisterScope well after startup, while other goroutines are
+// already minting and parsing tokens), so descriptors is guarded by mu.
+type ScopeRegistry struct {
+	mu          sync.RWMutex
+	descriptors map[scope]ScopeDescriptor
+}
+
+func newScopeRegistry() *ScopeRegistry {
+	return &ScopeRegistry{descriptors: map[scope]ScopeDescriptor{}}
+}
+
+// Register adds d to the registry under d.Name, replacing any previous descriptor of the same name.
+func (r *ScopeRegistry) Register(d ScopeDescriptor) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.descriptors[scope(d.Name)] = d
+}
+
+func (r *ScopeRegistry) get(s scope) (ScopeDescriptor, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, ok := r.descriptors[s]
+	return d, ok
+}
+
+// RegisterScope makes scope d available to GenerateScopedToken and ParseAndVerifyToken.
+func (service *Service) RegisterScope(d ScopeDescriptor) {
+	service.scopes.Register(d)
+}